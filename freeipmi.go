@@ -0,0 +1,177 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// freeipmi is a transport implementation built on top of the freeipmi suite
+// (ipmi-raw in particular), for systems where ipmitool isn't available.
+// It's selected by setting Connection.Interface to "freeipmi".
+type freeipmi struct {
+	*Connection
+
+	configPath string
+}
+
+func newFreeIPMITransport(c *Connection) transport {
+	return &freeipmi{Connection: c}
+}
+
+func (f *freeipmi) open() error {
+	return nil
+}
+
+func (f *freeipmi) close() error {
+	if f.configPath == "" {
+		return nil
+	}
+
+	if err := os.Remove(f.configPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing stale config pipe: %w", err)
+	}
+
+	return nil
+}
+
+func (f *freeipmi) send(req *Request, res Response) error {
+	path, err := f.createConfigPipe()
+	if err != nil {
+		return fmt.Errorf("error creating config pipe: %w", err)
+	}
+
+	args := append([]string{"--config-file=" + path}, rawEncode(requestToBytes(req))...)
+
+	cmd := f.cmd(args...)
+
+	var stdout strings.Builder
+	var stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		// nobody is ever going to open the pipe for reading, so remove
+		// it here instead of leaving the write goroutine to do it.
+		os.Remove(path) //nolint:errcheck
+
+		return fmt.Errorf("start %s: %w", cmd.Path, err)
+	}
+
+	go f.writeConfig(path)
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("run %s %s: %s (%w)",
+			cmd.Path, strings.Join(cmd.Args, " "), stderr.String(), err)
+	}
+
+	return responseFromString(stdout.String(), res)
+}
+
+func (f *freeipmi) Console() error {
+	return fmt.Errorf("console is not supported by the freeipmi transport")
+}
+
+func (f *freeipmi) cmd(args ...string) *exec.Cmd {
+	path := f.Path
+	if path == "" {
+		path = "ipmi-raw"
+	}
+
+	opts := append(f.options(), args...)
+
+	return exec.Command(path, opts...)
+}
+
+func (f *freeipmi) options() []string {
+	options := []string{
+		"-h", f.Hostname,
+	}
+
+	if f.Port != 0 {
+		options = append(options, "-p", fmt.Sprint(f.Port))
+	}
+
+	return options
+}
+
+// createConfigPipe creates a FIFO under os.TempDir() and returns its path.
+// Passing the config through a named pipe, rather than argv or a plain
+// tempfile, keeps the username/password out of ps(1) output and off disk.
+// The caller must only start the write side (writeConfig) after ipmi-raw,
+// which opens the read end, has actually started: writeConfig blocks until
+// a reader shows up, so a pipe nobody reads from leaks its writer goroutine.
+func (f *freeipmi) createConfigPipe() (string, error) {
+	path, err := randomPipePath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		return "", fmt.Errorf("error creating config pipe: %w", err)
+	}
+
+	f.configPath = path
+
+	return path, nil
+}
+
+// writeConfig opens path for writing and writes the freeipmi config to it.
+// It must only be called once the reader (ipmi-raw) is known to be
+// running, since the open blocks until a reader connects.
+func (f *freeipmi) writeConfig(path string) {
+	pipe, err := os.OpenFile(path, os.O_WRONLY, 0o600) //nolint:gosec
+	if err != nil {
+		return
+	}
+
+	// ipmi-raw has opened its end for reading, so the pipe's directory
+	// entry can be removed without losing the write.
+	os.Remove(path) //nolint:errcheck
+
+	_, _ = pipe.WriteString(f.config())
+	pipe.Close() //nolint:errcheck
+}
+
+func (f *freeipmi) config() string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "driver-type LAN_2_0")
+	fmt.Fprintln(&b, "privilege-level ADMIN")
+	fmt.Fprintf(&b, "username %s\n", f.Username)
+	fmt.Fprintf(&b, "password %s\n", f.Password)
+
+	return b.String()
+}
+
+// randomPipePath returns a path under os.TempDir() that doesn't exist yet,
+// suitable for syscall.Mkfifo.
+func randomPipePath() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random pipe name: %w", err)
+	}
+
+	name := fmt.Sprintf("goipmi-%x", b)
+	return filepath.Join(os.TempDir(), name), nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// IPMIError is returned by the tool transport when ipmitool's stderr
+// identifies the completion code a failed command came back with, so
+// callers can branch on it the same way they would with the native LAN
+// transport: errors.As(err, &ipmiErr); ipmiErr.CompletionCode == NodeBusy.
+type IPMIError struct {
+	CompletionCode CompletionCode
+	NetFn          NetworkFunction
+	Command        Command
+	Message        string
+}
+
+func (e *IPMIError) Error() string {
+	return fmt.Sprintf("ipmitool: %s (netfn=%#02x cmd=%#02x rsp=%#02x)",
+		e.Message, uint8(e.NetFn), uint8(e.Command), uint8(e.CompletionCode))
+}
+
+// ipmitoolErrorPattern matches lines like:
+//
+//	Unable to send RAW command (channel=0x0 netfn=0x6 lun=0x0 cmd=0x1 rsp=0xc1): Invalid command
+var ipmitoolErrorPattern = regexp.MustCompile(
+	`(?i)netfn=(0x[0-9a-f]+).*?cmd=(0x[0-9a-f]+).*?rsp=(0x[0-9a-f]+)\)\s*:?\s*(.*)`)
+
+// parseIPMIError recognizes ipmitool's stderr patterns for a failed raw
+// command and, if it finds one, returns a typed *IPMIError describing it.
+// It returns nil if stderr doesn't match a known pattern, in which case the
+// caller should fall back to reporting the raw exec error.
+func parseIPMIError(stderr string) *IPMIError {
+	m := ipmitoolErrorPattern.FindStringSubmatch(stderr)
+	if m == nil {
+		return nil
+	}
+
+	netFn, err := strconv.ParseUint(m[1], 0, 8)
+	if err != nil {
+		return nil
+	}
+
+	command, err := strconv.ParseUint(m[2], 0, 8)
+	if err != nil {
+		return nil
+	}
+
+	rsp, err := strconv.ParseUint(m[3], 0, 8)
+	if err != nil {
+		return nil
+	}
+
+	message := completionCodeMessages[CompletionCode(rsp)]
+	if message == "" {
+		message = m[4]
+	}
+
+	return &IPMIError{
+		CompletionCode: CompletionCode(rsp),
+		NetFn:          NetworkFunction(netFn),
+		Command:        Command(command),
+		Message:        message,
+	}
+}
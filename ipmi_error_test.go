@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import "testing"
+
+func TestParseIPMIError(t *testing.T) {
+	tests := []struct {
+		name    string
+		stderr  string
+		wantNil bool
+		netFn   NetworkFunction
+		command Command
+		code    CompletionCode
+	}{
+		{
+			name:    "raw command error",
+			stderr:  "Unable to send RAW command (channel=0x0 netfn=0x6 lun=0x0 cmd=0x1 rsp=0xc1): Invalid command",
+			netFn:   0x6,
+			command: 0x1,
+			code:    0xc1,
+		},
+		{
+			name:    "bare rsp variant",
+			stderr:  "Error sending Chassis Control command (netfn=0x0 cmd=0x2 rsp=0xc0)",
+			netFn:   0x0,
+			command: 0x2,
+			code:    0xc0,
+		},
+		{
+			name:    "unrecognized stderr",
+			stderr:  "ipmitool: command not found",
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseIPMIError(tt.stderr)
+
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("parseIPMIError(%q) = %+v, want nil", tt.stderr, got)
+				}
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("parseIPMIError(%q) = nil, want non-nil", tt.stderr)
+			}
+
+			if got.NetFn != tt.netFn {
+				t.Errorf("NetFn = %#x, want %#x", got.NetFn, tt.netFn)
+			}
+
+			if got.Command != tt.command {
+				t.Errorf("Command = %#x, want %#x", got.Command, tt.command)
+			}
+
+			if got.CompletionCode != tt.code {
+				t.Errorf("CompletionCode = %#x, want %#x", got.CompletionCode, tt.code)
+			}
+		})
+	}
+}
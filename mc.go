@@ -0,0 +1,444 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+)
+
+// ResetKind selects the kind of reset issued by MC.Reset.
+type ResetKind uint8
+
+const (
+	// ResetCold powers the BMC off and back on.
+	ResetCold ResetKind = iota
+	// ResetWarm restarts the BMC without a full power cycle.
+	ResetWarm
+)
+
+// LANConfig holds the subset of the LAN Configuration Parameters that
+// callers typically need: the channel's address and VLAN assignment.
+type LANConfig struct {
+	IP      net.IP
+	MAC     net.HardwareAddr
+	Netmask net.IP
+	Gateway net.IP
+	VLAN    uint16
+}
+
+// SensorReading is a single decoded SDR/sensor reading. Raw is the
+// unscaled byte Get Sensor Reading returns — it is NOT a calibrated unit
+// (e.g. not necessarily degrees Celsius). Converting it requires applying
+// the sensor's M/B/Rexp/Bexp/linearization from its full SDR, which this
+// package doesn't decode; callers that need calibrated values should read
+// the full SDR for Number themselves.
+type SensorReading struct {
+	Name   string
+	Number uint8
+	Raw    uint8
+}
+
+// SELRecord is a single decoded System Event Log entry.
+type SELRecord struct {
+	ID           uint16
+	SensorType   uint8
+	SensorNumber uint8
+	EventType    uint8
+	EventData    [3]byte
+}
+
+// SelfTestResult is the decoded response to a Get Self Test Results command.
+type SelfTestResult struct {
+	Code   uint8
+	Detail uint8
+}
+
+// MC wraps the common Management Controller operations so that callers
+// don't have to hand-roll raw NetFn/Command pairs for everyday inventory
+// and monitoring tasks. It's implemented on top of Client.Send, so it
+// works the same way over the tool and LAN transports.
+type MC interface {
+	// GUID returns the BMC's Device GUID (Get Device GUID, 0x08).
+	GUID() (uuid.UUID, error)
+	// LANConfig returns the LAN Configuration Parameters for channel.
+	LANConfig(channel int) (LANConfig, error)
+	// SensorTemps walks the SDR repository and returns raw (uncalibrated)
+	// readings for the temperature-type sensors it finds.
+	SensorTemps() ([]SensorReading, error)
+	// SELEntries returns the entries currently in the System Event Log.
+	SELEntries() ([]SELRecord, error)
+	// Reset issues a cold or warm reset of the BMC.
+	Reset(kind ResetKind) error
+	// SelfTest returns the BMC's self test results (Get Self Test Results,
+	// 0x04).
+	SelfTest() (SelfTestResult, error)
+}
+
+const (
+	netFnApp       = NetworkFunction(0x06)
+	netFnStorage   = NetworkFunction(0x0a)
+	netFnTransport = NetworkFunction(0x0c)
+	netFnSE        = NetworkFunction(0x04)
+
+	commandGetDeviceGUID      = Command(0x08)
+	commandGetLANConfigParams = Command(0x02)
+	commandGetSELEntry        = Command(0x43)
+	commandColdReset          = Command(0x02)
+	commandWarmReset          = Command(0x03)
+	commandGetSelfTestResults = Command(0x04)
+	commandReserveSDRRepo     = Command(0x22)
+	commandGetSDR             = Command(0x23)
+	commandGetSensorReading   = Command(0x2d)
+
+	sensorTypeTemperature = 0x01
+)
+
+type mc struct {
+	client *Client
+}
+
+// MC returns a Management Controller client bound to c.
+func (c *Client) MC() MC {
+	return &mc{client: c}
+}
+
+func (m *mc) GUID() (uuid.UUID, error) {
+	req := &Request{
+		NetworkFunction: netFnApp,
+		Command:         commandGetDeviceGUID,
+	}
+
+	res := &struct {
+		GUID [16]byte
+	}{}
+
+	if err := m.client.Send(req, res); err != nil {
+		return uuid.UUID{}, fmt.Errorf("error getting device GUID: %w", err)
+	}
+
+	return decodeGUID(res.GUID)
+}
+
+// decodeGUID converts a Get Device GUID response body, which comes back
+// least-significant byte first, into a standard big-endian uuid.UUID.
+func decodeGUID(raw [16]byte) (uuid.UUID, error) {
+	var reversed [16]byte
+	for i, b := range raw {
+		reversed[15-i] = b
+	}
+
+	return uuid.FromBytes(reversed[:])
+}
+
+func (m *mc) LANConfig(channel int) (LANConfig, error) {
+	const (
+		paramIPAddress = 3
+		paramMAC       = 5
+		paramSubnet    = 6
+		paramGateway   = 12
+		paramVLAN      = 20
+	)
+
+	get := func(param uint8, size int) ([]byte, error) {
+		req := &Request{
+			NetworkFunction: netFnTransport,
+			Command:         commandGetLANConfigParams,
+			Data: &struct {
+				Channel   uint8
+				Parameter uint8
+				SetSel    uint8
+				BlockSel  uint8
+			}{
+				Channel:   uint8(channel) & 0x0f,
+				Parameter: param,
+			},
+		}
+
+		res := make([]byte, size+1) // +1 for the leading parameter revision byte
+		if err := m.client.Send(req, &res); err != nil {
+			return nil, fmt.Errorf("error getting LAN config parameter %d: %w", param, err)
+		}
+
+		return res[1:], nil
+	}
+
+	var cfg LANConfig
+
+	if b, err := get(paramIPAddress, 4); err != nil {
+		return cfg, err
+	} else {
+		cfg.IP = net.IPv4(b[0], b[1], b[2], b[3])
+	}
+
+	if b, err := get(paramMAC, 6); err != nil {
+		return cfg, err
+	} else {
+		cfg.MAC = net.HardwareAddr(b)
+	}
+
+	if b, err := get(paramSubnet, 4); err != nil {
+		return cfg, err
+	} else {
+		cfg.Netmask = net.IPv4(b[0], b[1], b[2], b[3])
+	}
+
+	if b, err := get(paramGateway, 4); err != nil {
+		return cfg, err
+	} else {
+		cfg.Gateway = net.IPv4(b[0], b[1], b[2], b[3])
+	}
+
+	if b, err := get(paramVLAN, 2); err != nil {
+		return cfg, err
+	} else {
+		cfg.VLAN = uint16(b[0]) | uint16(b[1])<<8&0x0fff
+	}
+
+	return cfg, nil
+}
+
+func (m *mc) SensorTemps() ([]SensorReading, error) {
+	records, err := m.sdrRepository()
+	if err != nil {
+		return nil, fmt.Errorf("error walking SDR repository: %w", err)
+	}
+
+	var readings []SensorReading
+
+	for _, sdr := range records {
+		if sdr.sensorType != sensorTypeTemperature {
+			continue
+		}
+
+		raw, err := m.sensorReading(sdr.number)
+		if err != nil {
+			return nil, fmt.Errorf("error reading sensor %d: %w", sdr.number, err)
+		}
+
+		readings = append(readings, SensorReading{
+			Name:   sdr.name,
+			Number: sdr.number,
+			Raw:    raw,
+		})
+	}
+
+	return readings, nil
+}
+
+func (m *mc) SELEntries() ([]SELRecord, error) {
+	var (
+		records      []SELRecord
+		nextRecordID uint16 = 0x0000
+	)
+
+	for {
+		req := &Request{
+			NetworkFunction: netFnStorage,
+			Command:         commandGetSELEntry,
+			Data: &struct {
+				ReservationID uint16
+				RecordID      uint16
+				Offset        uint8
+				BytesToRead   uint8
+			}{
+				RecordID:    nextRecordID,
+				BytesToRead: 0xff,
+			},
+		}
+
+		res := &struct {
+			NextRecordID uint16
+			RecordData   [16]byte
+		}{}
+
+		if err := m.client.Send(req, res); err != nil {
+			return nil, fmt.Errorf("error getting SEL entry %#x: %w", nextRecordID, err)
+		}
+
+		records = append(records, decodeSELRecord(res.RecordData))
+
+		if res.NextRecordID == 0xffff {
+			break
+		}
+
+		nextRecordID = res.NextRecordID
+	}
+
+	return records, nil
+}
+
+// decodeSELRecord decodes the 16-byte body of a Get SEL Entry response
+// (RecordID@0-1, RecordType@2, Timestamp@3-6, Generator ID@7-8, EvM
+// Rev@9, Sensor Type@10, Sensor Number@11, Event Dir/Type@12, Event Data
+// 1-3@13-15) into a SELRecord.
+func decodeSELRecord(raw [16]byte) SELRecord {
+	return SELRecord{
+		ID:           uint16(raw[0]) | uint16(raw[1])<<8,
+		SensorType:   raw[10],
+		SensorNumber: raw[11],
+		EventType:    raw[12],
+		EventData:    [3]byte{raw[13], raw[14], raw[15]},
+	}
+}
+
+func (m *mc) Reset(kind ResetKind) error {
+	cmd := commandColdReset
+	if kind == ResetWarm {
+		cmd = commandWarmReset
+	}
+
+	req := &Request{
+		NetworkFunction: netFnApp,
+		Command:         cmd,
+	}
+
+	if err := m.client.Send(req, nil); err != nil {
+		return fmt.Errorf("error resetting BMC: %w", err)
+	}
+
+	return nil
+}
+
+// sdr is the subset of an SDR repository record that SensorTemps needs.
+type sdr struct {
+	number     uint8
+	sensorType uint8
+	name       string
+}
+
+// sdrRepository walks the SDR repository via Reserve SDR Repository (0x22)
+// / Get SDR (0x23), following the record-to-record linked list until the
+// controller reports 0xffff as the next record ID.
+func (m *mc) sdrRepository() ([]sdr, error) {
+	reserveReq := &Request{
+		NetworkFunction: netFnStorage,
+		Command:         commandReserveSDRRepo,
+	}
+
+	reserveRes := &struct {
+		ReservationID uint16
+	}{}
+
+	if err := m.client.Send(reserveReq, reserveRes); err != nil {
+		return nil, fmt.Errorf("error reserving SDR repository: %w", err)
+	}
+
+	var (
+		records      []sdr
+		nextRecordID uint16
+	)
+
+	for {
+		req := &Request{
+			NetworkFunction: netFnStorage,
+			Command:         commandGetSDR,
+			Data: &struct {
+				ReservationID uint16
+				RecordID      uint16
+				Offset        uint8
+				BytesToRead   uint8
+			}{
+				ReservationID: reserveRes.ReservationID,
+				RecordID:      nextRecordID,
+				BytesToRead:   0xff,
+			},
+		}
+
+		res := &struct {
+			NextRecordID uint16
+			RecordData   [64]byte
+		}{}
+
+		if err := m.client.Send(req, res); err != nil {
+			return nil, fmt.Errorf("error getting SDR %#x: %w", nextRecordID, err)
+		}
+
+		records = append(records, decodeSDR(res.RecordData))
+
+		if res.NextRecordID == 0xffff {
+			break
+		}
+
+		nextRecordID = res.NextRecordID
+	}
+
+	return records, nil
+}
+
+// decodeSDR decodes the fields SensorTemps needs out of a 64-byte Get SDR
+// response body: Sensor Number@7, Sensor Type@12, and the ID String
+// Type/Length Code@48 followed by the string itself starting at byte 49.
+func decodeSDR(raw [64]byte) sdr {
+	nameLen := int(raw[48] & 0x1f)
+	name := ""
+	if nameLen > 0 && 49+nameLen <= len(raw) {
+		name = string(raw[49 : 49+nameLen])
+	}
+
+	return sdr{
+		number:     raw[7],
+		sensorType: raw[12],
+		name:       name,
+	}
+}
+
+// sensorReading issues a Get Sensor Reading (0x2d) for the given sensor
+// number and returns the raw, unscaled reading byte.
+func (m *mc) sensorReading(number uint8) (uint8, error) {
+	req := &Request{
+		NetworkFunction: netFnSE,
+		Command:         commandGetSensorReading,
+		Data: &struct {
+			SensorNumber uint8
+		}{
+			SensorNumber: number,
+		},
+	}
+
+	res := &struct {
+		Reading uint8
+		Status  uint8
+		States  [2]uint8
+	}{}
+
+	if err := m.client.Send(req, res); err != nil {
+		return 0, err
+	}
+
+	return res.Reading, nil
+}
+
+func (m *mc) SelfTest() (SelfTestResult, error) {
+	req := &Request{
+		NetworkFunction: netFnApp,
+		Command:         commandGetSelfTestResults,
+	}
+
+	res := &struct {
+		Code   uint8
+		Detail uint8
+	}{}
+
+	if err := m.client.Send(req, res); err != nil {
+		return SelfTestResult{}, fmt.Errorf("error running self test: %w", err)
+	}
+
+	return SelfTestResult{Code: res.Code, Detail: res.Detail}, nil
+}
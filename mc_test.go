@@ -0,0 +1,86 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDecodeGUID(t *testing.T) {
+	want := uuid.MustParse("00112233-4455-6677-8899-aabbccddeeff")
+
+	// Get Device GUID returns the bytes least-significant byte first.
+	raw := [16]byte{
+		0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88,
+		0x77, 0x66, 0x55, 0x44, 0x33, 0x22, 0x11, 0x00,
+	}
+
+	got, err := decodeGUID(raw)
+	if err != nil {
+		t.Fatalf("decodeGUID() error = %v", err)
+	}
+
+	if got != want {
+		t.Errorf("decodeGUID() = %s, want %s", got, want)
+	}
+}
+
+func TestDecodeSELRecord(t *testing.T) {
+	// RecordID=0x0001, RecordType=0x02, Timestamp (bytes 3-6), Generator
+	// ID (bytes 7-8), EvM Rev (byte 9), SensorType=0x07,
+	// SensorNumber=0x2a, Event Dir/Type=0x6f, Event Data 1-3=0x01,0xff,0xff.
+	raw := [16]byte{
+		0x01, 0x00, 0x02,
+		0xde, 0xad, 0xbe, 0xef,
+		0x20, 0x00,
+		0x04,
+		0x07, 0x2a, 0x6f,
+		0x01, 0xff, 0xff,
+	}
+
+	want := SELRecord{
+		ID:           1,
+		SensorType:   0x07,
+		SensorNumber: 0x2a,
+		EventType:    0x6f,
+		EventData:    [3]byte{0x01, 0xff, 0xff},
+	}
+
+	if got := decodeSELRecord(raw); got != want {
+		t.Errorf("decodeSELRecord() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeSDR(t *testing.T) {
+	var raw [64]byte
+	raw[7] = 0x12                   // sensor number
+	raw[12] = sensorTypeTemperature // sensor type
+	raw[48] = 0x03                  // ID string type/length code: 3 bytes
+	copy(raw[49:], "CPU")
+
+	want := sdr{
+		number:     0x12,
+		sensorType: sensorTypeTemperature,
+		name:       "CPU",
+	}
+
+	if got := decodeSDR(raw); got != want {
+		t.Errorf("decodeSDR() = %+v, want %+v", got, want)
+	}
+}
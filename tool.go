@@ -20,17 +20,38 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
 )
 
+// passwordHandle exposes the connection password to ipmitool without
+// passing it via argv, the environment, or a file that outlives the
+// process. The mechanism differs by platform: see tool_linux.go,
+// tool_windows.go and tool_other.go.
+type passwordHandle interface {
+	// arg is the value passed to ipmitool's -f flag.
+	arg() string
+	// extraFiles are appended to exec.Cmd.ExtraFiles; nil on platforms
+	// that pass the password by path instead of by fd.
+	extraFiles() []*os.File
+	// refresh is called before every invocation of ipmitool, giving the
+	// handle a chance to rewind the password or set up a fresh path for
+	// it, without yet doing anything that blocks on ipmitool reading it.
+	refresh() error
+	// start is called once ipmitool has successfully started (after
+	// cmd.Start returns nil), so the handle knows it's now safe to do
+	// any blocking write to a pipe that only ipmitool will ever read.
+	start() error
+	// close releases the handle and removes any trace of it from disk.
+	close() error
+}
+
 type tool struct {
 	*Connection
 
-	passwdFile *os.File
+	passwd passwordHandle
 }
 
 func newToolTransport(c *Connection) transport {
@@ -40,29 +61,16 @@ func newToolTransport(c *Connection) transport {
 func (t *tool) open() error {
 	var err error
 
-	// create a temporary file to store the password
-	t.passwdFile, err = os.CreateTemp("", "goipmi")
+	t.passwd, err = newPasswordHandle(t.Password)
 	if err != nil {
-		return fmt.Errorf("error creating temporary file: %w", err)
-	}
-
-	if err = os.Remove(t.passwdFile.Name()); err != nil {
-		t.passwdFile.Close() //nolint:errcheck
-
-		return fmt.Errorf("error removing temporary file: %w", err)
-	}
-
-	if _, err = t.passwdFile.WriteString(t.Password); err != nil {
-		t.passwdFile.Close() //nolint:errcheck
-
-		return fmt.Errorf("error writing password: %w", err)
+		return fmt.Errorf("error setting up password handle: %w", err)
 	}
 
 	return nil
 }
 
 func (t *tool) close() error {
-	return t.passwdFile.Close()
+	return t.passwd.close()
 }
 
 func (t *tool) send(req *Request, res Response) error {
@@ -71,7 +79,6 @@ func (t *tool) send(req *Request, res Response) error {
 
 	output, err := t.run(args...)
 	if err != nil {
-		// TODO: parse CompletionCode from stderr
 		return err
 	}
 
@@ -79,11 +86,24 @@ func (t *tool) send(req *Request, res Response) error {
 }
 
 func (t *tool) Console() error {
+	if err := t.passwd.refresh(); err != nil {
+		return fmt.Errorf("error refreshing the password handle: %w", err)
+	}
+
 	cmd := t.cmd("sol", "activate", "-e", "&")
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", cmd.Path, err)
+	}
+
+	if err := t.passwd.start(); err != nil {
+		return fmt.Errorf("error starting password handle: %w", err)
+	}
+
+	return cmd.Wait()
 }
 
 func (t *tool) options() []string {
@@ -95,7 +115,7 @@ func (t *tool) options() []string {
 	options := []string{
 		"-H", t.Hostname,
 		"-U", t.Username,
-		"-f", "/proc/self/fd/3",
+		"-f", t.passwd.arg(),
 		"-I", intf,
 	}
 
@@ -115,27 +135,36 @@ func (t *tool) cmd(args ...string) *exec.Cmd {
 	}
 
 	cmd := exec.Command(path, opts...)
-	cmd.ExtraFiles = []*os.File{
-		t.passwdFile,
-	}
+	cmd.ExtraFiles = t.passwd.extraFiles()
 
 	return cmd
 }
 
 func (t *tool) run(args ...string) (string, error) {
+	if err := t.passwd.refresh(); err != nil {
+		return "", fmt.Errorf("error refreshing the password handle: %w", err)
+	}
+
 	cmd := t.cmd(args...)
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	// rewind the password file
-	if _, err := t.passwdFile.Seek(0, io.SeekStart); err != nil {
-		return "", fmt.Errorf("error seeking the password file: %w", err)
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("start %s: %w", cmd.Path, err)
+	}
+
+	if err := t.passwd.start(); err != nil {
+		return "", fmt.Errorf("error starting password handle: %w", err)
 	}
 
-	err := cmd.Run()
+	err := cmd.Wait()
 	if err != nil {
+		if ipmiErr := parseIPMIError(stderr.String()); ipmiErr != nil {
+			return "", ipmiErr
+		}
+
 		return "", fmt.Errorf("run %s %s: %s (%s)",
 			cmd.Path, strings.Join(cmd.Args, " "), stderr.String(), err)
 	}
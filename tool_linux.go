@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// fdPasswordHandle passes the password through an inherited, unlinked fd,
+// which ipmitool reads via /proc/self/fd/3.
+type fdPasswordHandle struct {
+	file *os.File
+}
+
+func newPasswordHandle(password string) (passwordHandle, error) {
+	file, err := os.CreateTemp("", "goipmi")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary file: %w", err)
+	}
+
+	if err := os.Remove(file.Name()); err != nil {
+		file.Close() //nolint:errcheck
+
+		return nil, fmt.Errorf("error removing temporary file: %w", err)
+	}
+
+	if _, err := file.WriteString(password); err != nil {
+		file.Close() //nolint:errcheck
+
+		return nil, fmt.Errorf("error writing password: %w", err)
+	}
+
+	return &fdPasswordHandle{file: file}, nil
+}
+
+func (h *fdPasswordHandle) arg() string {
+	return "/proc/self/fd/3"
+}
+
+func (h *fdPasswordHandle) extraFiles() []*os.File {
+	return []*os.File{h.file}
+}
+
+func (h *fdPasswordHandle) refresh() error {
+	if _, err := h.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking the password file: %w", err)
+	}
+
+	return nil
+}
+
+// start is a no-op: the password is already sitting in h.file, which was
+// passed to ipmitool as an inherited fd, so there's nothing left to do
+// once the process has started.
+func (h *fdPasswordHandle) start() error {
+	return nil
+}
+
+func (h *fdPasswordHandle) close() error {
+	return h.file.Close()
+}
@@ -0,0 +1,94 @@
+//go:build !linux && !windows
+
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// fifoPasswordHandle passes the password through a FIFO created fresh for
+// every invocation of ipmitool, since /proc/self/fd doesn't exist on
+// macOS/*BSD. The write, which blocks until ipmitool opens its end for
+// reading, only starts once ipmitool is confirmed to be running (see
+// start); as soon as the read end opens the FIFO is unlinked, so nothing
+// survives on disk once the write completes, and a missing ipmitool
+// binary can't leak a goroutine blocked on open forever.
+type fifoPasswordHandle struct {
+	password string
+	path     string
+}
+
+func newPasswordHandle(password string) (passwordHandle, error) {
+	return &fifoPasswordHandle{password: password}, nil
+}
+
+func (h *fifoPasswordHandle) arg() string {
+	return h.path
+}
+
+func (h *fifoPasswordHandle) extraFiles() []*os.File {
+	return nil
+}
+
+func (h *fifoPasswordHandle) refresh() error {
+	path, err := randomPipePath()
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		return fmt.Errorf("error creating password pipe: %w", err)
+	}
+
+	h.path = path
+
+	return nil
+}
+
+func (h *fifoPasswordHandle) start() error {
+	path := h.path
+
+	go func() {
+		pipe, err := os.OpenFile(path, os.O_WRONLY, 0o600) //nolint:gosec
+		if err != nil {
+			return
+		}
+
+		os.Remove(path) //nolint:errcheck
+
+		_, _ = pipe.WriteString(h.password)
+		pipe.Close() //nolint:errcheck
+	}()
+
+	return nil
+}
+
+func (h *fifoPasswordHandle) close() error {
+	if h.path == "" {
+		return nil
+	}
+
+	if err := os.Remove(h.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing stale password pipe: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2014 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// namedPipePasswordHandle passes the password through a named pipe created
+// fresh for every invocation of ipmitool, since neither /proc/self/fd nor
+// Unix FIFOs exist on Windows. The pipe is created by refresh, before
+// ipmitool is launched, but ConnectNamedPipe (which blocks until ipmitool
+// opens its end) only runs from start, once ipmitool is confirmed to have
+// launched — otherwise a missing ipmitool binary would leak a goroutine
+// blocked on ConnectNamedPipe forever.
+type namedPipePasswordHandle struct {
+	password string
+	path     string
+	handle   windows.Handle
+}
+
+func newPasswordHandle(password string) (passwordHandle, error) {
+	return &namedPipePasswordHandle{password: password}, nil
+}
+
+func (h *namedPipePasswordHandle) arg() string {
+	return h.path
+}
+
+func (h *namedPipePasswordHandle) extraFiles() []*os.File {
+	return nil
+}
+
+func (h *namedPipePasswordHandle) refresh() error {
+	name, err := randomName()
+	if err != nil {
+		return err
+	}
+
+	h.path = `\\.\pipe\` + name
+
+	pathPtr, err := windows.UTF16PtrFromString(h.path)
+	if err != nil {
+		return fmt.Errorf("error encoding pipe path: %w", err)
+	}
+
+	handle, err := windows.CreateNamedPipe(
+		pathPtr,
+		windows.PIPE_ACCESS_OUTBOUND,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_WAIT,
+		1, 512, 512, 0, nil,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating named pipe: %w", err)
+	}
+
+	h.handle = handle
+
+	return nil
+}
+
+func (h *namedPipePasswordHandle) start() error {
+	handle := h.handle
+
+	go func() {
+		defer windows.CloseHandle(handle) //nolint:errcheck
+
+		if err := windows.ConnectNamedPipe(handle, nil); err != nil {
+			return
+		}
+
+		var written uint32
+		_ = windows.WriteFile(handle, []byte(h.password), &written, nil)
+	}()
+
+	return nil
+}
+
+func (h *namedPipePasswordHandle) close() error {
+	if h.handle == 0 {
+		return nil
+	}
+
+	return windows.CloseHandle(h.handle)
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random pipe name: %w", err)
+	}
+
+	return fmt.Sprintf("goipmi-%x", b), nil
+}